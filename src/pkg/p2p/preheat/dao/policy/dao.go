@@ -0,0 +1,158 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"context"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/orm"
+	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/p2p/preheat/models/policy"
+)
+
+// DAO defines the interface to access the policy data model.
+type DAO interface {
+	// Count returns the total count of policies according to the query.
+	Count(ctx context.Context, query *q.Query) (total int64, err error)
+	// Create the policy schema, returns the ID of the created policy.
+	Create(ctx context.Context, schema *policy.Schema) (id int64, err error)
+	// Update the policy schema, only the properties specified by "props" will be updated if it is set.
+	Update(ctx context.Context, schema *policy.Schema, props ...string) (err error)
+	// Get the policy schema by id.
+	Get(ctx context.Context, id int64) (schema *policy.Schema, err error)
+	// GetByName the policy schema by name.
+	GetByName(ctx context.Context, projectID int64, name string) (schema *policy.Schema, err error)
+	// Delete the policy schema by id.
+	Delete(ctx context.Context, id int64) (err error)
+	// List the policy schemas by query.
+	List(ctx context.Context, query *q.Query) (schemas []*policy.Schema, err error)
+
+	// CreateHistory snapshots the current state of a policy schema into the p2p_preheat_policy_history table.
+	CreateHistory(ctx context.Context, history *policy.History) (id int64, err error)
+	// ListHistories lists the snapshots recorded for a given policy, ordered by version descending.
+	ListHistories(ctx context.Context, policyID int64) (histories []*policy.History, err error)
+	// GetHistory gets the snapshot recorded for a given policy and version.
+	GetHistory(ctx context.Context, policyID int64, version int64) (history *policy.History, err error)
+}
+
+type dao struct{}
+
+// New creates an instance of the default policy DAO.
+func New() DAO {
+	return &dao{}
+}
+
+// Count returns the total count of policies according to the query.
+func (d *dao) Count(ctx context.Context, query *q.Query) (int64, error) {
+	qs, err := orm.QuerySetter(ctx, &policy.Schema{}, query)
+	if err != nil {
+		return 0, err
+	}
+	return qs.Count()
+}
+
+// Create the policy schema, returns the ID of the created policy.
+func (d *dao) Create(ctx context.Context, schema *policy.Schema) (int64, error) {
+	o, err := orm.FromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return o.Insert(schema)
+}
+
+// Update the policy schema, only the properties specified by "props" will be updated if it is set.
+func (d *dao) Update(ctx context.Context, schema *policy.Schema, props ...string) error {
+	o, err := orm.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+	n, err := o.Update(schema, props...)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errors.NotFoundError(nil).WithMessage("policy %d not found", schema.ID)
+	}
+	return nil
+}
+
+// Get the policy schema by id.
+func (d *dao) Get(ctx context.Context, id int64) (*policy.Schema, error) {
+	o, err := orm.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	schema := &policy.Schema{ID: id}
+	if err := o.Read(schema); err != nil {
+		if e := orm.AsNotFoundError(err, "policy %d not found", id); e != nil {
+			return nil, e
+		}
+		return nil, err
+	}
+	return schema, nil
+}
+
+// GetByName the policy schema by name.
+func (d *dao) GetByName(ctx context.Context, projectID int64, name string) (*policy.Schema, error) {
+	qs, err := orm.QuerySetter(ctx, &policy.Schema{}, &q.Query{
+		Keywords: map[string]interface{}{
+			"project_id": projectID,
+			"name":       name,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &policy.Schema{}
+	if err := qs.One(schema); err != nil {
+		if e := orm.AsNotFoundError(err, "policy %s not found under project %d", name, projectID); e != nil {
+			return nil, e
+		}
+		return nil, err
+	}
+	return schema, nil
+}
+
+// Delete the policy schema by id.
+func (d *dao) Delete(ctx context.Context, id int64) error {
+	o, err := orm.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+	n, err := o.Delete(&policy.Schema{ID: id})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errors.NotFoundError(nil).WithMessage("policy %d not found", id)
+	}
+	return nil
+}
+
+// List the policy schemas by query.
+func (d *dao) List(ctx context.Context, query *q.Query) ([]*policy.Schema, error) {
+	qs, err := orm.QuerySetter(ctx, &policy.Schema{}, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var schemas []*policy.Schema
+	if _, err := qs.All(&schemas); err != nil {
+		return nil, err
+	}
+	return schemas, nil
+}
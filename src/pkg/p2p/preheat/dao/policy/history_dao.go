@@ -0,0 +1,73 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"context"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/orm"
+	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/p2p/preheat/models/policy"
+)
+
+// CreateHistory snapshots the current state of a policy schema into the p2p_preheat_policy_history table.
+func (d *dao) CreateHistory(ctx context.Context, history *policy.History) (int64, error) {
+	o, err := orm.FromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return o.Insert(history)
+}
+
+// ListHistories lists the snapshots recorded for a given policy, ordered by version descending.
+func (d *dao) ListHistories(ctx context.Context, policyID int64) ([]*policy.History, error) {
+	qs, err := orm.QuerySetter(ctx, &policy.History{}, &q.Query{
+		Keywords: map[string]interface{}{
+			"policy_id": policyID,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var histories []*policy.History
+	if _, err := qs.OrderBy("-version").All(&histories); err != nil {
+		return nil, err
+	}
+	return histories, nil
+}
+
+// GetHistory gets the snapshot recorded for a given policy and version.
+func (d *dao) GetHistory(ctx context.Context, policyID int64, version int64) (*policy.History, error) {
+	qs, err := orm.QuerySetter(ctx, &policy.History{}, &q.Query{
+		Keywords: map[string]interface{}{
+			"policy_id": policyID,
+			"version":   version,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	history := &policy.History{}
+	if err := qs.One(history); err != nil {
+		if e := orm.AsNotFoundError(err, "version %d of policy %d not found", version, policyID); e != nil {
+			return nil, e
+		}
+		return nil, err
+	}
+	return history, nil
+}
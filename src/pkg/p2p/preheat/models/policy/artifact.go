@@ -0,0 +1,30 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import "time"
+
+// Artifact is the evaluation-time view of an artifact that policy filters and
+// conditions are matched against. It is intentionally decoupled from the
+// full artifact model so that filter/condition evaluation has no dependency
+// on the artifact package's lifecycle.
+type Artifact struct {
+	Repository   string
+	Tag          string
+	Labels       []string
+	VulnSeverity int
+	PushSourceIP string
+	PushTime     time.Time
+}
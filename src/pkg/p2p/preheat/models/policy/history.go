@@ -0,0 +1,55 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import "time"
+
+// History is a point-in-time snapshot of a policy Schema, recorded on every
+// Create/Update so that a prior version can be inspected or rolled back to.
+type History struct {
+	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
+	PolicyID     int64     `orm:"column(policy_id)" json:"policy_id"`
+	Version      int64     `orm:"column(version)" json:"version"`
+	Name         string    `orm:"column(name)" json:"name"`
+	Description  string    `orm:"column(description)" json:"description"`
+	ProjectID    int64     `orm:"column(project_id)" json:"project_id"`
+	ProviderID   int64     `orm:"column(provider_id)" json:"provider_id"`
+	FiltersStr   string    `orm:"column(filters)" json:"-"`
+	Filters      []*Filter `orm:"-" json:"filters"`
+	TriggerStr   string    `orm:"column(trigger)" json:"-"`
+	Trigger      *Trigger  `orm:"-" json:"trigger"`
+	Enabled      bool      `orm:"column(enabled)" json:"enabled"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+}
+
+// TableName sets the name of table in DB that maps to the model.
+func (h *History) TableName() string {
+	return "p2p_preheat_policy_history"
+}
+
+// Snapshot builds a History row that captures the current state of schema.
+func Snapshot(schema *Schema) *History {
+	return &History{
+		PolicyID:    schema.ID,
+		Version:     schema.Version,
+		Name:        schema.Name,
+		Description: schema.Description,
+		ProjectID:   schema.ProjectID,
+		ProviderID:  schema.ProviderID,
+		FiltersStr:  schema.FiltersStr,
+		TriggerStr:  schema.TriggerStr,
+		Enabled:     schema.Enabled,
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import "time"
+
+// FilterType represents the type of filter.
+type FilterType string
+
+// Filter type definitions.
+const (
+	FilterTypeRepository    FilterType = "repository"
+	FilterTypeTag           FilterType = "tag"
+	FilterTypeLabel         FilterType = "label"
+	FilterTypeVulnerability FilterType = "vulnerability"
+	// FilterTypeCondition is a generic, expression based filter type whose
+	// value is evaluated by a registered condition.ConditionEvaluator.
+	FilterTypeCondition FilterType = "condition"
+)
+
+// TriggerType represents the type of trigger.
+type TriggerType string
+
+// Trigger type definitions.
+const (
+	TriggerTypeManual     TriggerType = "manual"
+	TriggerTypeScheduled  TriggerType = "scheduled"
+	TriggerTypeEventBased TriggerType = "event_based"
+)
+
+// Filter holds a filter type and its value.
+type Filter struct {
+	Type  FilterType  `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// TriggerSettings keeps the settings of the trigger.
+type TriggerSettings struct {
+	Cron string `json:"cron"`
+}
+
+// Trigger of the policy.
+type Trigger struct {
+	Type     TriggerType      `json:"type"`
+	Settings *TriggerSettings `json:"trigger_setting"`
+}
+
+// Schema is the data model of a preheat policy.
+type Schema struct {
+	ID          int64  `orm:"pk;auto;column(id)" json:"id"`
+	Name        string `orm:"column(name)" json:"name"`
+	Description string `orm:"column(description)" json:"description"`
+	ProjectID   int64  `orm:"column(project_id)" json:"project_id"`
+	ProviderID  int64  `orm:"column(provider_id)" json:"provider_id"`
+	// Version is a monotonically increasing number bumped on every Create
+	// and Update so p2p_preheat_policy_history rows can be addressed unambiguously.
+	Version      int64     `orm:"column(version)" json:"version"`
+	FiltersStr   string    `orm:"column(filters)" json:"-"`
+	Filters      []*Filter `orm:"-" json:"filters"`
+	TriggerStr   string    `orm:"column(trigger)" json:"-"`
+	Trigger      *Trigger  `orm:"-" json:"trigger"`
+	Enabled      bool      `orm:"column(enabled)" json:"enabled"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName sets the name of table in DB that maps to the model.
+func (s *Schema) TableName() string {
+	return "p2p_preheat_policy"
+}
@@ -0,0 +1,101 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package condition
+
+import (
+	"context"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/p2p/preheat/models/policy"
+)
+
+// Names of the boolean composition evaluators.
+const (
+	AndName = "and"
+	OrName  = "or"
+	NotName = "not"
+)
+
+func init() {
+	Register(AndName, &andEvaluator{})
+	Register(OrName, &orEvaluator{})
+	Register(NotName, &notEvaluator{})
+}
+
+// andEvaluator fulfills when every nested condition fulfills.
+// Options: {"conditions": [{"name": ..., "options": {...}}, ...]}.
+type andEvaluator struct{}
+
+func (e *andEvaluator) Fulfills(ctx context.Context, artifact *policy.Artifact, options map[string]interface{}) (bool, error) {
+	exprs, err := expressionsOption(options, "conditions")
+	if err != nil {
+		return false, err
+	}
+
+	for _, expr := range exprs {
+		ok, err := Evaluate(ctx, expr, artifact)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// orEvaluator fulfills when at least one nested condition fulfills.
+// Options: {"conditions": [{"name": ..., "options": {...}}, ...]}.
+type orEvaluator struct{}
+
+func (e *orEvaluator) Fulfills(ctx context.Context, artifact *policy.Artifact, options map[string]interface{}) (bool, error) {
+	exprs, err := expressionsOption(options, "conditions")
+	if err != nil {
+		return false, err
+	}
+
+	for _, expr := range exprs {
+		ok, err := Evaluate(ctx, expr, artifact)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// notEvaluator fulfills when its single nested condition does not fulfill.
+// Options: {"conditions": [{"name": ..., "options": {...}}]}.
+type notEvaluator struct{}
+
+func (e *notEvaluator) Fulfills(ctx context.Context, artifact *policy.Artifact, options map[string]interface{}) (bool, error) {
+	exprs, err := expressionsOption(options, "conditions")
+	if err != nil {
+		return false, err
+	}
+	if len(exprs) != 1 {
+		return false, errors.Errorf("%q takes exactly one nested condition, got %d", NotName, len(exprs))
+	}
+
+	ok, err := Evaluate(ctx, exprs[0], artifact)
+	if err != nil {
+		return false, err
+	}
+	return !ok, nil
+}
@@ -0,0 +1,53 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package condition
+
+import (
+	"context"
+	"net"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/p2p/preheat/models/policy"
+)
+
+// CIDRMatchName is the registered name of cidrMatchEvaluator.
+const CIDRMatchName = "cidr_match"
+
+func init() {
+	Register(CIDRMatchName, &cidrMatchEvaluator{})
+}
+
+// cidrMatchEvaluator fulfills when the artifact's push source IP falls inside
+// a CIDR block. Options: {"cidr": "10.0.0.0/8"}.
+type cidrMatchEvaluator struct{}
+
+func (e *cidrMatchEvaluator) Fulfills(_ context.Context, artifact *policy.Artifact, options map[string]interface{}) (bool, error) {
+	cidr, err := stringOption(options, "cidr")
+	if err != nil {
+		return false, err
+	}
+
+	_, block, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid cidr %q", cidr)
+	}
+
+	ip := net.ParseIP(artifact.PushSourceIP)
+	if ip == nil {
+		return false, errors.Errorf("invalid push source ip %q", artifact.PushSourceIP)
+	}
+
+	return block.Contains(ip), nil
+}
@@ -0,0 +1,74 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package condition
+
+import (
+	"context"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/p2p/preheat/models/policy"
+)
+
+// NumericCompareName is the registered name of numericCompareEvaluator.
+const NumericCompareName = "numeric_compare"
+
+func init() {
+	Register(NumericCompareName, &numericCompareEvaluator{})
+}
+
+// numericCompareEvaluator fulfills when an artifact's numeric field compares
+// as specified against a value. Options: {"field": "vuln_severity",
+// "op": "<"|"<="|">"|">="|"=="|"!=", "value": 5}.
+type numericCompareEvaluator struct{}
+
+func (e *numericCompareEvaluator) Fulfills(_ context.Context, artifact *policy.Artifact, options map[string]interface{}) (bool, error) {
+	field, err := stringOption(options, "field")
+	if err != nil {
+		return false, err
+	}
+	op, err := stringOption(options, "op")
+	if err != nil {
+		return false, err
+	}
+	want, err := float64Option(options, "value")
+	if err != nil {
+		return false, err
+	}
+
+	var got float64
+	switch field {
+	case "vuln_severity":
+		got = float64(artifact.VulnSeverity)
+	default:
+		return false, errors.Errorf("unsupported field %q", field)
+	}
+
+	switch op {
+	case "<":
+		return got < want, nil
+	case "<=":
+		return got <= want, nil
+	case ">":
+		return got > want, nil
+	case ">=":
+		return got >= want, nil
+	case "==":
+		return got == want, nil
+	case "!=":
+		return got != want, nil
+	default:
+		return false, errors.Errorf("unsupported op %q", op)
+	}
+}
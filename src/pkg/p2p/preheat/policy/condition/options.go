@@ -0,0 +1,86 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package condition
+
+import (
+	"encoding/json"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/p2p/preheat/models/policy"
+)
+
+// stringOption reads a required string option.
+func stringOption(options map[string]interface{}, key string) (string, error) {
+	v, ok := options[key]
+	if !ok {
+		return "", errors.Errorf("option %q is required", key)
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", errors.Errorf("option %q must be a string", key)
+	}
+	return s, nil
+}
+
+// float64Option reads a required numeric option. JSON numbers decode as float64.
+func float64Option(options map[string]interface{}, key string) (float64, error) {
+	v, ok := options[key]
+	if !ok {
+		return 0, errors.Errorf("option %q is required", key)
+	}
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case int:
+		return float64(n), nil
+	default:
+		return 0, errors.Errorf("option %q must be a number", key)
+	}
+}
+
+// fieldValue resolves the value of the artifact field named by the "field" option.
+func fieldValue(artifact *policy.Artifact, field string) (string, error) {
+	switch field {
+	case "repository":
+		return artifact.Repository, nil
+	case "tag":
+		return artifact.Tag, nil
+	default:
+		return "", errors.Errorf("unsupported field %q", field)
+	}
+}
+
+// expressionsOption reads a required list of nested condition expressions,
+// used by the boolean composition evaluators.
+func expressionsOption(options map[string]interface{}, key string) ([]*Expression, error) {
+	v, ok := options[key]
+	if !ok {
+		return nil, errors.Errorf("option %q is required", key)
+	}
+
+	// Re-marshal/unmarshal rather than type-asserting element by element:
+	// options come from json.Unmarshal into interface{}, so nested
+	// conditions arrive as []interface{} of map[string]interface{}.
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.Wrapf(err, "option %q", key)
+	}
+
+	var exprs []*Expression
+	if err := json.Unmarshal(raw, &exprs); err != nil {
+		return nil, errors.Wrapf(err, "option %q", key)
+	}
+	return exprs, nil
+}
@@ -0,0 +1,57 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package condition
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/p2p/preheat/models/policy"
+)
+
+// RegexMatchName is the registered name of regexMatchEvaluator.
+const RegexMatchName = "regex_match"
+
+func init() {
+	Register(RegexMatchName, &regexMatchEvaluator{})
+}
+
+// regexMatchEvaluator fulfills when the named artifact field matches a regular
+// expression. Options: {"field": "repository"|"tag", "pattern": "..."}.
+type regexMatchEvaluator struct{}
+
+func (e *regexMatchEvaluator) Fulfills(_ context.Context, artifact *policy.Artifact, options map[string]interface{}) (bool, error) {
+	field, err := stringOption(options, "field")
+	if err != nil {
+		return false, err
+	}
+	pattern, err := stringOption(options, "pattern")
+	if err != nil {
+		return false, err
+	}
+
+	value, err := fieldValue(artifact, field)
+	if err != nil {
+		return false, err
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid pattern %q", pattern)
+	}
+
+	return re.MatchString(value), nil
+}
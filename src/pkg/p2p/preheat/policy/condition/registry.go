@@ -0,0 +1,82 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package condition implements the evaluator registry backing the
+// policy.FilterTypeCondition filter. A condition filter's Value is a
+// structured Expression naming a registered ConditionEvaluator; Evaluate
+// resolves and runs it against an artifact. Built-in evaluators register
+// themselves on import of this package; downstream code can plug in its own
+// by calling Register from any package imported by the binary.
+package condition
+
+import (
+	"context"
+	"sync"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/p2p/preheat/models/policy"
+)
+
+// Expression is the structured value carried by a policy.FilterTypeCondition
+// filter. Name must match a registered ConditionEvaluator; Options is passed
+// to it verbatim.
+type Expression struct {
+	Name    string                 `json:"name"`
+	Options map[string]interface{} `json:"options"`
+}
+
+// ConditionEvaluator evaluates whether an artifact fulfills a condition.
+// Implementations must be stateless and safe for concurrent use, as a single
+// registered instance is shared across all policy evaluations.
+type ConditionEvaluator interface {
+	// Fulfills reports whether artifact satisfies the condition configured by options.
+	Fulfills(ctx context.Context, artifact *policy.Artifact, options map[string]interface{}) (bool, error)
+}
+
+var (
+	mu         sync.RWMutex
+	evaluators = map[string]ConditionEvaluator{}
+)
+
+// Register adds evaluator to the global registry under name, overwriting any
+// evaluator previously registered under the same name. Built-in evaluators
+// call this from an init() function; downstream packages can do the same to
+// plug in custom condition types.
+func Register(name string, evaluator ConditionEvaluator) {
+	mu.Lock()
+	defer mu.Unlock()
+	evaluators[name] = evaluator
+}
+
+// Get looks up the evaluator registered under name.
+func Get(name string) (evaluator ConditionEvaluator, ok bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	evaluator, ok = evaluators[name]
+	return
+}
+
+// Evaluate resolves expr's evaluator from the registry and runs it against artifact.
+func Evaluate(ctx context.Context, expr *Expression, artifact *policy.Artifact) (bool, error) {
+	if expr == nil {
+		return false, errors.New("condition expression can not be nil")
+	}
+
+	evaluator, ok := Get(expr.Name)
+	if !ok {
+		return false, errors.Errorf("condition %q is not registered", expr.Name)
+	}
+
+	return evaluator.Fulfills(ctx, artifact, expr.Options)
+}
@@ -0,0 +1,68 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package condition
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goharbor/harbor/src/pkg/p2p/preheat/models/policy"
+)
+
+type fakeEvaluator struct {
+	fulfills bool
+	err      error
+}
+
+func (f *fakeEvaluator) Fulfills(_ context.Context, _ *policy.Artifact, _ map[string]interface{}) (bool, error) {
+	return f.fulfills, f.err
+}
+
+func TestRegisterAndGet(t *testing.T) {
+	Register("fake_for_test", &fakeEvaluator{fulfills: true})
+
+	got, ok := Get("fake_for_test")
+	require.True(t, ok)
+	assert.Equal(t, &fakeEvaluator{fulfills: true}, got)
+
+	_, ok = Get("not_registered")
+	assert.False(t, ok)
+}
+
+func TestEvaluateDispatchesToRegisteredEvaluator(t *testing.T) {
+	Register("fake_true_for_test", &fakeEvaluator{fulfills: true})
+	Register("fake_false_for_test", &fakeEvaluator{fulfills: false})
+
+	ok, err := Evaluate(context.Background(), &Expression{Name: "fake_true_for_test"}, &policy.Artifact{})
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = Evaluate(context.Background(), &Expression{Name: "fake_false_for_test"}, &policy.Artifact{})
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestEvaluateUnregisteredNameErrors(t *testing.T) {
+	_, err := Evaluate(context.Background(), &Expression{Name: "does_not_exist"}, &policy.Artifact{})
+	assert.Error(t, err)
+}
+
+func TestEvaluateNilExpressionErrors(t *testing.T) {
+	_, err := Evaluate(context.Background(), nil, &policy.Artifact{})
+	assert.Error(t, err)
+}
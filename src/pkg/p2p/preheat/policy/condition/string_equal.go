@@ -0,0 +1,50 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package condition
+
+import (
+	"context"
+
+	"github.com/goharbor/harbor/src/pkg/p2p/preheat/models/policy"
+)
+
+// StringEqualName is the registered name of stringEqualEvaluator.
+const StringEqualName = "string_equal"
+
+func init() {
+	Register(StringEqualName, &stringEqualEvaluator{})
+}
+
+// stringEqualEvaluator fulfills when the named artifact field equals a value
+// exactly. Options: {"field": "repository"|"tag", "value": "..."}.
+type stringEqualEvaluator struct{}
+
+func (e *stringEqualEvaluator) Fulfills(_ context.Context, artifact *policy.Artifact, options map[string]interface{}) (bool, error) {
+	field, err := stringOption(options, "field")
+	if err != nil {
+		return false, err
+	}
+	want, err := stringOption(options, "value")
+	if err != nil {
+		return false, err
+	}
+
+	got, err := fieldValue(artifact, field)
+	if err != nil {
+		return false, err
+	}
+
+	return got == want, nil
+}
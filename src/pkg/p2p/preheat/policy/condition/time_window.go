@@ -0,0 +1,158 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package condition
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/p2p/preheat/models/policy"
+)
+
+// TimeWindowName is the registered name of timeWindowEvaluator.
+const TimeWindowName = "time_window"
+
+func init() {
+	Register(TimeWindowName, &timeWindowEvaluator{})
+}
+
+// timeWindowEvaluator fulfills when the artifact's push time falls inside a
+// window. Three option shapes are supported:
+//
+//   - absolute range: {"start": RFC3339, "end": RFC3339}
+//   - recurring business-hours window: {"days": ["Mon", ...],
+//     "start_hour": 9, "end_hour": 18}, evaluated in UTC. Days accept either
+//     the abbreviated ("Mon") or full ("Monday") weekday name, case-insensitively.
+//   - cron: {"cron": "<standard 5-field cron expression>"}, fulfilled when the
+//     artifact's push time, truncated to the minute, is itself a scheduled
+//     run of the expression (UTC)
+type timeWindowEvaluator struct{}
+
+func (e *timeWindowEvaluator) Fulfills(_ context.Context, artifact *policy.Artifact, options map[string]interface{}) (bool, error) {
+	if _, ok := options["start"]; ok {
+		return e.fulfillsRange(artifact, options)
+	}
+	if _, ok := options["days"]; ok {
+		return e.fulfillsRecurring(artifact, options)
+	}
+	if _, ok := options["cron"]; ok {
+		return e.fulfillsCron(artifact, options)
+	}
+	return false, errors.New(`time_window requires "start"/"end", "days"/"start_hour"/"end_hour", or "cron" options`)
+}
+
+func (e *timeWindowEvaluator) fulfillsRange(artifact *policy.Artifact, options map[string]interface{}) (bool, error) {
+	startStr, err := stringOption(options, "start")
+	if err != nil {
+		return false, err
+	}
+	endStr, err := stringOption(options, "end")
+	if err != nil {
+		return false, err
+	}
+
+	start, err := time.Parse(time.RFC3339, startStr)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid start %q", startStr)
+	}
+	end, err := time.Parse(time.RFC3339, endStr)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid end %q", endStr)
+	}
+
+	t := artifact.PushTime
+	return !t.Before(start) && !t.After(end), nil
+}
+
+func (e *timeWindowEvaluator) fulfillsRecurring(artifact *policy.Artifact, options map[string]interface{}) (bool, error) {
+	daysOpt, ok := options["days"].([]interface{})
+	if !ok {
+		return false, errors.New(`option "days" must be a list of weekday names`)
+	}
+	startHour, err := float64Option(options, "start_hour")
+	if err != nil {
+		return false, err
+	}
+	endHour, err := float64Option(options, "end_hour")
+	if err != nil {
+		return false, err
+	}
+
+	t := artifact.PushTime.UTC()
+	matched := false
+	for _, d := range daysOpt {
+		name, ok := d.(string)
+		if !ok {
+			return false, errors.New(`option "days" must be a list of weekday names`)
+		}
+		weekday, err := parseWeekday(name)
+		if err != nil {
+			return false, err
+		}
+		if t.Weekday() == weekday {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return false, nil
+	}
+
+	hour := float64(t.Hour())
+	return hour >= startHour && hour < endHour, nil
+}
+
+// weekdaysByName maps both abbreviated ("Mon") and full ("Monday") weekday
+// names, case-insensitively, to time.Weekday.
+var weekdaysByName = func() map[string]time.Weekday {
+	m := make(map[string]time.Weekday, 14)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		m[strings.ToLower(d.String())] = d
+		m[strings.ToLower(d.String()[:3])] = d
+	}
+	return m
+}()
+
+// parseWeekday accepts an abbreviated ("Mon") or full ("Monday") weekday
+// name, case-insensitively, and returns an error for anything else rather
+// than silently failing to match.
+func parseWeekday(name string) (time.Weekday, error) {
+	weekday, ok := weekdaysByName[strings.ToLower(name)]
+	if !ok {
+		return 0, errors.Errorf("unrecognized weekday %q", name)
+	}
+	return weekday, nil
+}
+
+// fulfillsCron reports whether artifact's push time is itself a scheduled
+// run of the given standard 5-field cron expression.
+func (e *timeWindowEvaluator) fulfillsCron(artifact *policy.Artifact, options map[string]interface{}) (bool, error) {
+	spec, err := stringOption(options, "cron")
+	if err != nil {
+		return false, err
+	}
+
+	sched, err := cron.ParseStandard(spec)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid cron expression %q", spec)
+	}
+
+	t := artifact.PushTime.UTC().Truncate(time.Minute)
+	return sched.Next(t.Add(-time.Minute)).Equal(t), nil
+}
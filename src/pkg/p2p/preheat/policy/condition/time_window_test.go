@@ -0,0 +1,115 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package condition
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goharbor/harbor/src/pkg/p2p/preheat/models/policy"
+)
+
+// 2023-08-09 is a Wednesday.
+var aWednesday = time.Date(2023, 8, 9, 10, 30, 0, 0, time.UTC)
+
+func TestTimeWindowRecurringAcceptsAbbreviatedDayNames(t *testing.T) {
+	e := &timeWindowEvaluator{}
+	ok, err := e.Fulfills(context.Background(), &policy.Artifact{PushTime: aWednesday}, map[string]interface{}{
+		"days":       []interface{}{"Mon", "Wed", "Fri"},
+		"start_hour": float64(9),
+		"end_hour":   float64(18),
+	})
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestTimeWindowRecurringAcceptsFullDayNames(t *testing.T) {
+	e := &timeWindowEvaluator{}
+	ok, err := e.Fulfills(context.Background(), &policy.Artifact{PushTime: aWednesday}, map[string]interface{}{
+		"days":       []interface{}{"Wednesday"},
+		"start_hour": float64(9),
+		"end_hour":   float64(18),
+	})
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestTimeWindowRecurringOutsideHoursDoesNotMatch(t *testing.T) {
+	e := &timeWindowEvaluator{}
+	ok, err := e.Fulfills(context.Background(), &policy.Artifact{PushTime: aWednesday}, map[string]interface{}{
+		"days":       []interface{}{"Wed"},
+		"start_hour": float64(11),
+		"end_hour":   float64(18),
+	})
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestTimeWindowRecurringUnknownDayNameErrors(t *testing.T) {
+	e := &timeWindowEvaluator{}
+	_, err := e.Fulfills(context.Background(), &policy.Artifact{PushTime: aWednesday}, map[string]interface{}{
+		"days":       []interface{}{"Funday"},
+		"start_hour": float64(9),
+		"end_hour":   float64(18),
+	})
+	assert.Error(t, err)
+}
+
+func TestTimeWindowRange(t *testing.T) {
+	e := &timeWindowEvaluator{}
+	ok, err := e.Fulfills(context.Background(), &policy.Artifact{PushTime: aWednesday}, map[string]interface{}{
+		"start": "2023-08-01T00:00:00Z",
+		"end":   "2023-08-31T00:00:00Z",
+	})
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestTimeWindowCronMatchesScheduledMinute(t *testing.T) {
+	e := &timeWindowEvaluator{}
+	// "0 9-18 * * 1-5" fires every hour on the hour, 9am-6pm, Mon-Fri.
+	ok, err := e.Fulfills(context.Background(), &policy.Artifact{
+		PushTime: time.Date(2023, 8, 9, 9, 0, 0, 0, time.UTC),
+	}, map[string]interface{}{"cron": "0 9-18 * * 1-5"})
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestTimeWindowCronRejectsOffSchedulePushTime(t *testing.T) {
+	e := &timeWindowEvaluator{}
+	ok, err := e.Fulfills(context.Background(), &policy.Artifact{
+		PushTime: time.Date(2023, 8, 9, 9, 30, 0, 0, time.UTC),
+	}, map[string]interface{}{"cron": "0 9-18 * * 1-5"})
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestTimeWindowCronInvalidExpressionErrors(t *testing.T) {
+	e := &timeWindowEvaluator{}
+	_, err := e.Fulfills(context.Background(), &policy.Artifact{PushTime: aWednesday}, map[string]interface{}{
+		"cron": "not a cron expression",
+	})
+	assert.Error(t, err)
+}
+
+func TestTimeWindowRequiresOneOfTheSupportedShapes(t *testing.T) {
+	e := &timeWindowEvaluator{}
+	_, err := e.Fulfills(context.Background(), &policy.Artifact{PushTime: aWednesday}, map[string]interface{}{})
+	assert.Error(t, err)
+}
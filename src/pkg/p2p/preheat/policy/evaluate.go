@@ -0,0 +1,202 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"context"
+	"path"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/p2p/preheat/models/policy"
+	"github.com/goharbor/harbor/src/pkg/p2p/preheat/policy/condition"
+)
+
+// ArtifactLister resolves the current candidate artifact set that a policy's
+// filters are evaluated against. The preheat controller must reassign it to
+// the real artifact manager during application bootstrap, the same way the
+// condition package's evaluators are wired up by import. The default errors
+// out instead of returning no candidates, so a controller that forgets to
+// wire it fails loudly rather than serving an always-empty preview.
+var ArtifactLister = func(ctx context.Context, projectID int64, query *q.Query) ([]*policy.Artifact, error) {
+	return nil, errors.New("policy.ArtifactLister is not wired up; the preheat controller must reassign it to the artifact manager during bootstrap")
+}
+
+// EvaluateOptions controls how Evaluate resolves the candidate artifact set.
+type EvaluateOptions struct {
+	// ProjectID scopes evaluation to a single project.
+	ProjectID int64
+	// Query further narrows the candidate artifact set, e.g. by repository or tag keywords.
+	Query *q.Query
+}
+
+// FilterMatch reports how many candidate artifacts a single filter matched.
+type FilterMatch struct {
+	Filter     *policy.Filter `json:"filter"`
+	MatchCount int            `json:"match_count"`
+}
+
+// Rejection records why a candidate artifact was excluded from the result.
+type Rejection struct {
+	Artifact       *policy.Artifact `json:"artifact"`
+	RejectedFilter *policy.Filter   `json:"rejected_filter"`
+}
+
+// EvaluateResult is the outcome of dry-running a policy's filters against the
+// current artifact set.
+type EvaluateResult struct {
+	Matched       []*policy.Artifact `json:"matched"`
+	FilterMatches []*FilterMatch     `json:"filter_matches"`
+	Rejected      []*Rejection       `json:"rejected"`
+}
+
+// Evaluate resolves schema's filters against the current artifact set without
+// persisting the policy or enqueuing preheat jobs. It matches each candidate
+// through matchFilter, the same unexported primitive Matches uses to decide
+// what the live preheat trigger path would preheat, so a preview can never
+// diverge from what would actually be preheated.
+func (m *manager) Evaluate(ctx context.Context, schema *policy.Schema, opts EvaluateOptions) (*EvaluateResult, error) {
+	if schema == nil {
+		return nil, errors.New("policy schema can not be nil")
+	}
+
+	filters, err := resolveFilters(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := ArtifactLister(ctx, opts.ProjectID, opts.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	filterMatches := make([]*FilterMatch, len(filters))
+	for i, f := range filters {
+		filterMatches[i] = &FilterMatch{Filter: f}
+	}
+
+	result := &EvaluateResult{FilterMatches: filterMatches}
+	for _, artifact := range candidates {
+		var rejectedBy *policy.Filter
+		for i, f := range filters {
+			ok, err := matchFilter(ctx, f, artifact)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				filterMatches[i].MatchCount++
+			} else if rejectedBy == nil {
+				rejectedBy = f
+			}
+		}
+
+		if rejectedBy == nil {
+			result.Matched = append(result.Matched, artifact)
+		} else {
+			result.Rejected = append(result.Rejected, &Rejection{Artifact: artifact, RejectedFilter: rejectedBy})
+		}
+	}
+
+	return result, nil
+}
+
+// Matches reports whether artifact satisfies every filter of schema, stopping
+// at the first filter that does not match. Evaluate does not call Matches: it
+// runs matchFilter on every filter of every candidate to count matches per
+// filter, and treats an artifact as matched only when every filter matched,
+// the same rule Matches applies.
+func (m *manager) Matches(ctx context.Context, schema *policy.Schema, artifact *policy.Artifact) (bool, error) {
+	if schema == nil {
+		return false, errors.New("policy schema can not be nil")
+	}
+
+	filters, err := resolveFilters(schema)
+	if err != nil {
+		return false, err
+	}
+
+	for _, f := range filters {
+		ok, err := matchFilter(ctx, f, artifact)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// resolveFilters returns schema's filters with their values normalized to
+// the concrete types matchFilter expects. If the caller already populated
+// schema.Filters (e.g. the preview endpoint decoding a policy straight from
+// a JSON request body), those values are only JSON's generic map[string]any
+// and float64, so they still need normalizing exactly like FiltersStr does.
+func resolveFilters(schema *policy.Schema) ([]*policy.Filter, error) {
+	if schema.Filters != nil {
+		if err := normalizeFilterValues(schema.Filters); err != nil {
+			return nil, err
+		}
+		return schema.Filters, nil
+	}
+	return parseFilters(schema.FiltersStr)
+}
+
+// matchFilter reports whether artifact satisfies a single filter. An artifact
+// is preheated by a policy only when it satisfies every filter of that
+// policy. matchFilter itself is unexported: callers must go through Matches
+// (live trigger) or Evaluate (preview) so the two paths cannot drift apart.
+func matchFilter(ctx context.Context, f *policy.Filter, artifact *policy.Artifact) (bool, error) {
+	switch f.Type {
+	case policy.FilterTypeRepository:
+		pattern, ok := f.Value.(string)
+		if !ok {
+			return false, errors.Errorf("repository filter value must be a string")
+		}
+		return path.Match(pattern, artifact.Repository)
+	case policy.FilterTypeTag:
+		pattern, ok := f.Value.(string)
+		if !ok {
+			return false, errors.Errorf("tag filter value must be a string")
+		}
+		return path.Match(pattern, artifact.Tag)
+	case policy.FilterTypeLabel:
+		label, ok := f.Value.(string)
+		if !ok {
+			return false, errors.Errorf("label filter value must be a string")
+		}
+		for _, l := range artifact.Labels {
+			if l == label {
+				return true, nil
+			}
+		}
+		return false, nil
+	case policy.FilterTypeVulnerability:
+		threshold, ok := f.Value.(int)
+		if !ok {
+			return false, errors.Errorf("vulnerability filter value must be an int")
+		}
+		return artifact.VulnSeverity < threshold, nil
+	case policy.FilterTypeCondition:
+		expr, ok := f.Value.(*condition.Expression)
+		if !ok {
+			return false, errors.Errorf("condition filter value must be a parsed condition expression")
+		}
+		return condition.Evaluate(ctx, expr, artifact)
+	default:
+		return false, errors.Errorf("unsupported filter type %q", f.Type)
+	}
+}
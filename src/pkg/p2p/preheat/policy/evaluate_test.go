@@ -0,0 +1,137 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/p2p/preheat/models/policy"
+	"github.com/goharbor/harbor/src/pkg/p2p/preheat/policy/condition"
+)
+
+func withArtifactLister(t *testing.T, artifacts []*policy.Artifact) {
+	t.Helper()
+	original := ArtifactLister
+	ArtifactLister = func(_ context.Context, _ int64, _ *q.Query) ([]*policy.Artifact, error) {
+		return artifacts, nil
+	}
+	t.Cleanup(func() { ArtifactLister = original })
+}
+
+func TestMatchesRequiresEveryFilter(t *testing.T) {
+	m := &manager{}
+	schema := &policy.Schema{
+		Filters: []*policy.Filter{
+			{Type: policy.FilterTypeRepository, Value: "library/*"},
+			{Type: policy.FilterTypeTag, Value: "v1.*"},
+		},
+	}
+
+	ok, err := m.Matches(context.Background(), schema, &policy.Artifact{Repository: "library/hello", Tag: "v1.0"})
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = m.Matches(context.Background(), schema, &policy.Artifact{Repository: "library/hello", Tag: "v2.0"})
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestMatchesNilSchemaErrors(t *testing.T) {
+	m := &manager{}
+	_, err := m.Matches(context.Background(), nil, &policy.Artifact{})
+	assert.Error(t, err)
+}
+
+func TestEvaluateSplitsMatchedAndRejected(t *testing.T) {
+	m := &manager{}
+	schema := &policy.Schema{
+		Filters: []*policy.Filter{
+			{Type: policy.FilterTypeRepository, Value: "library/*"},
+		},
+	}
+	withArtifactLister(t, []*policy.Artifact{
+		{Repository: "library/hello"},
+		{Repository: "other/hello"},
+	})
+
+	result, err := m.Evaluate(context.Background(), schema, EvaluateOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Matched, 1)
+	assert.Equal(t, "library/hello", result.Matched[0].Repository)
+	require.Len(t, result.Rejected, 1)
+	assert.Equal(t, "other/hello", result.Rejected[0].Artifact.Repository)
+	assert.Equal(t, schema.Filters[0], result.Rejected[0].RejectedFilter)
+	require.Len(t, result.FilterMatches, 1)
+	assert.Equal(t, 1, result.FilterMatches[0].MatchCount)
+}
+
+// TestEvaluateErrorsWhenArtifactListerUnwired guards against the preview
+// endpoint silently reporting zero matches when the preheat controller
+// forgets to reassign ArtifactLister during bootstrap.
+func TestEvaluateErrorsWhenArtifactListerUnwired(t *testing.T) {
+	m := &manager{}
+	schema := &policy.Schema{
+		Filters: []*policy.Filter{
+			{Type: policy.FilterTypeRepository, Value: "library/*"},
+		},
+	}
+
+	_, err := m.Evaluate(context.Background(), schema, EvaluateOptions{})
+	assert.Error(t, err)
+}
+
+func TestEvaluateNilSchemaErrors(t *testing.T) {
+	m := &manager{}
+	_, err := m.Evaluate(context.Background(), nil, EvaluateOptions{})
+	assert.Error(t, err)
+}
+
+func TestMatchFilterUnsupportedTypeErrors(t *testing.T) {
+	_, err := matchFilter(context.Background(), &policy.Filter{Type: "bogus"}, &policy.Artifact{})
+	assert.Error(t, err)
+}
+
+// TestEvaluateNormalizesInMemoryFilterValues guards against a regression
+// where a schema whose Filters were set directly (rather than parsed from
+// FiltersStr) skipped value normalization entirely. That's exactly how the
+// preview endpoint builds its schema: decoding a JSON request body leaves a
+// vulnerability filter's Value as float64 and a condition filter's Value as
+// a raw map[string]interface{}, neither of which matchFilter's type
+// assertions accept.
+func TestEvaluateNormalizesInMemoryFilterValues(t *testing.T) {
+	m := &manager{}
+	schema := &policy.Schema{
+		Filters: []*policy.Filter{
+			{Type: policy.FilterTypeVulnerability, Value: float64(5)},
+			{Type: policy.FilterTypeCondition, Value: map[string]interface{}{
+				"name": condition.StringEqualName,
+				"options": map[string]interface{}{
+					"field": "repository",
+					"value": "library/hello",
+				},
+			}},
+		},
+	}
+	withArtifactLister(t, []*policy.Artifact{{Repository: "library/hello", VulnSeverity: 1}})
+
+	result, err := m.Evaluate(context.Background(), schema, EvaluateOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.FilterMatches, 2)
+}
@@ -20,14 +20,22 @@ import (
 	"strconv"
 
 	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/orm"
 	"github.com/goharbor/harbor/src/lib/q"
 	dao "github.com/goharbor/harbor/src/pkg/p2p/preheat/dao/policy"
 	"github.com/goharbor/harbor/src/pkg/p2p/preheat/models/policy"
+	"github.com/goharbor/harbor/src/pkg/p2p/preheat/policy/condition"
 )
 
 // Mgr is a global instance of policy manager
 var Mgr = New()
 
+// withTransaction commits a policy write and its history snapshot
+// atomically. It is a package variable, rather than a direct call to
+// orm.WithTransaction, purely so tests can substitute a no-op transaction
+// without standing up a real database.
+var withTransaction = orm.WithTransaction
+
 // Manager manages the policy
 type Manager interface {
 	// Count returns the total count of policies according to the query
@@ -46,6 +54,20 @@ type Manager interface {
 	ListPolicies(ctx context.Context, query *q.Query) (schemas []*policy.Schema, err error)
 	// list policy schema under project
 	ListPoliciesByProject(ctx context.Context, project int64, query *q.Query) (schemas []*policy.Schema, err error)
+	// ListVersions lists the history of versions recorded for the given policy, newest first
+	ListVersions(ctx context.Context, policyID int64) (versions []*policy.History, err error)
+	// GetVersion gets the snapshot of the policy at the given version
+	GetVersion(ctx context.Context, policyID int64, version int64) (snapshot *policy.History, err error)
+	// Rollback restores the policy to the state it was in at the given version by
+	// re-issuing an Update from the recorded snapshot
+	Rollback(ctx context.Context, policyID int64, version int64) (err error)
+	// Evaluate resolves schema's filters against the current artifact set without
+	// persisting the policy or enqueuing preheat jobs
+	Evaluate(ctx context.Context, schema *policy.Schema, opts EvaluateOptions) (result *EvaluateResult, err error)
+	// Matches reports whether artifact satisfies every filter of schema. It and
+	// Evaluate both decide each filter with the same matchFilter function and
+	// both require every filter to match.
+	Matches(ctx context.Context, schema *policy.Schema, artifact *policy.Artifact) (bool, error)
 }
 
 type manager struct {
@@ -66,12 +88,53 @@ func (m *manager) Count(ctx context.Context, query *q.Query) (total int64, err e
 
 // Create the policy schema
 func (m *manager) Create(ctx context.Context, schema *policy.Schema) (id int64, err error) {
-	return m.dao.Create(ctx, schema)
+	schema.Version = 1
+
+	err = withTransaction(func(ctx context.Context) error {
+		id, err = m.dao.Create(ctx, schema)
+		if err != nil {
+			return err
+		}
+		schema.ID = id
+
+		_, err = m.dao.CreateHistory(ctx, policy.Snapshot(schema))
+		return err
+	})(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
 }
 
 // Update the policy schema, Only the properties specified by "props" will be updated if it is set
 func (m *manager) Update(ctx context.Context, schema *policy.Schema, props ...string) (err error) {
-	return m.dao.Update(ctx, schema, props...)
+	if len(props) > 0 {
+		props = append(props, "Version")
+	}
+
+	return withTransaction(func(ctx context.Context) error {
+		// Read the current version inside the same transaction as the update
+		// and the snapshot. The read takes no row lock, so two concurrent
+		// updates can compute the same next version; the later snapshot then
+		// fails on unique_policy_version and its whole transaction rolls back.
+		current, err := m.dao.Get(ctx, schema.ID)
+		if err != nil {
+			return err
+		}
+		schema.Version = current.Version + 1
+
+		if err := m.dao.Update(ctx, schema, props...); err != nil {
+			return err
+		}
+
+		updated, err := m.dao.Get(ctx, schema.ID)
+		if err != nil {
+			return err
+		}
+		_, err = m.dao.CreateHistory(ctx, policy.Snapshot(updated))
+		return err
+	})(ctx)
 }
 
 // Get the policy schema by id
@@ -132,6 +195,40 @@ func (m *manager) ListPoliciesByProject(ctx context.Context, project int64, quer
 	return m.ListPolicies(ctx, query)
 }
 
+// ListVersions lists the history of versions recorded for the given policy, newest first
+func (m *manager) ListVersions(ctx context.Context, policyID int64) (versions []*policy.History, err error) {
+	return m.dao.ListHistories(ctx, policyID)
+}
+
+// GetVersion gets the snapshot of the policy at the given version
+func (m *manager) GetVersion(ctx context.Context, policyID int64, version int64) (snapshot *policy.History, err error) {
+	return m.dao.GetHistory(ctx, policyID, version)
+}
+
+// Rollback restores the policy to the state it was in at the given version by
+// re-issuing an Update from the recorded snapshot
+func (m *manager) Rollback(ctx context.Context, policyID int64, version int64) (err error) {
+	snapshot, err := m.GetVersion(ctx, policyID, version)
+	if err != nil {
+		return err
+	}
+
+	// Pass the explicit column set recorded by a snapshot so Update doesn't
+	// touch CreationTime: it's auto_now_add, so an unrestricted Update call
+	// would write back the zero value on this in-memory schema rather than
+	// leaving the policy's original creation time alone.
+	return m.Update(ctx, &policy.Schema{
+		ID:          policyID,
+		Name:        snapshot.Name,
+		Description: snapshot.Description,
+		ProjectID:   snapshot.ProjectID,
+		ProviderID:  snapshot.ProviderID,
+		FiltersStr:  snapshot.FiltersStr,
+		TriggerStr:  snapshot.TriggerStr,
+		Enabled:     snapshot.Enabled,
+	}, "Name", "Description", "ProjectID", "ProviderID", "FiltersStr", "TriggerStr", "Enabled")
+}
+
 // parsePolicy parse policy model.
 func parsePolicy(schema *policy.Schema) (*policy.Schema, error) {
 	if schema == nil {
@@ -166,24 +263,68 @@ func parseFilters(filterStr string) ([]*policy.Filter, error) {
 		return nil, err
 	}
 
-	// Convert value type
-	// TODO: remove switch after UI bug #12579 fixed
+	if err := normalizeFilterValues(filters); err != nil {
+		return nil, err
+	}
+
+	return filters, nil
+}
+
+// normalizeFilterValues converts each filter's raw JSON-decoded Value into
+// the concrete type matchFilter expects: an int for FilterTypeVulnerability
+// and a *condition.Expression for FilterTypeCondition. It is idempotent, so
+// it is safe to run both on filters freshly unmarshalled from FiltersStr and
+// on filters supplied already in-memory, e.g. schema.Filters set directly by
+// the preview endpoint.
+// TODO: remove switch after UI bug #12579 fixed
+func normalizeFilterValues(filters []*policy.Filter) error {
 	for _, f := range filters {
-		if f.Type == policy.FilterTypeVulnerability {
+		switch f.Type {
+		case policy.FilterTypeVulnerability:
 			switch f.Value.(type) {
 			case string:
 				sev, err := strconv.ParseInt(f.Value.(string), 10, 32)
 				if err != nil {
-					return nil, errors.Wrapf(err, "parse filters")
+					return errors.Wrapf(err, "parse filters")
 				}
 				f.Value = (int)(sev)
 			case float64:
 				f.Value = (int)(f.Value.(float64))
 			}
+		case policy.FilterTypeCondition:
+			if _, ok := f.Value.(*condition.Expression); ok {
+				continue
+			}
+			expr, err := parseCondition(f.Value)
+			if err != nil {
+				return errors.Wrapf(err, "parse filters")
+			}
+			f.Value = expr
 		}
 	}
 
-	return filters, nil
+	return nil
+}
+
+// parseCondition converts the raw JSON value of a FilterTypeCondition filter
+// into a condition.Expression and confirms its evaluator is registered,
+// dispatching through the condition registry rather than a hard-coded switch.
+func parseCondition(value interface{}) (*condition.Expression, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	expr := &condition.Expression{}
+	if err := json.Unmarshal(raw, expr); err != nil {
+		return nil, err
+	}
+
+	if _, ok := condition.Get(expr.Name); !ok {
+		return nil, errors.Errorf("condition %q is not registered", expr.Name)
+	}
+
+	return expr, nil
 }
 
 // parseTrigger parse triggerStr to trigger.
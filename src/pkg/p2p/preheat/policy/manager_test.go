@@ -0,0 +1,199 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/p2p/preheat/models/policy"
+)
+
+// fakeDAO is a minimal in-memory stand-in for dao.DAO, enough to exercise
+// Manager's version-bump/rollback bookkeeping without a real database.
+type fakeDAO struct {
+	schemas    map[int64]*policy.Schema
+	histories  map[int64][]*policy.History
+	nextID     int64
+	createErr  error
+	historyErr error
+}
+
+func newFakeDAO() *fakeDAO {
+	return &fakeDAO{
+		schemas:   map[int64]*policy.Schema{},
+		histories: map[int64][]*policy.History{},
+	}
+}
+
+func (f *fakeDAO) Count(_ context.Context, _ *q.Query) (int64, error) {
+	return int64(len(f.schemas)), nil
+}
+
+func (f *fakeDAO) Create(_ context.Context, schema *policy.Schema) (int64, error) {
+	if f.createErr != nil {
+		return 0, f.createErr
+	}
+	f.nextID++
+	cp := *schema
+	cp.ID = f.nextID
+	f.schemas[cp.ID] = &cp
+	return cp.ID, nil
+}
+
+func (f *fakeDAO) Update(_ context.Context, schema *policy.Schema, _ ...string) error {
+	if _, ok := f.schemas[schema.ID]; !ok {
+		return assert.AnError
+	}
+	cp := *schema
+	f.schemas[cp.ID] = &cp
+	return nil
+}
+
+func (f *fakeDAO) Get(_ context.Context, id int64) (*policy.Schema, error) {
+	s, ok := f.schemas[id]
+	if !ok {
+		return nil, assert.AnError
+	}
+	cp := *s
+	return &cp, nil
+}
+
+func (f *fakeDAO) GetByName(_ context.Context, _ int64, _ string) (*policy.Schema, error) {
+	return nil, assert.AnError
+}
+
+func (f *fakeDAO) Delete(_ context.Context, id int64) error {
+	delete(f.schemas, id)
+	return nil
+}
+
+func (f *fakeDAO) List(_ context.Context, _ *q.Query) ([]*policy.Schema, error) {
+	var out []*policy.Schema
+	for _, s := range f.schemas {
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func (f *fakeDAO) CreateHistory(_ context.Context, history *policy.History) (int64, error) {
+	if f.historyErr != nil {
+		return 0, f.historyErr
+	}
+	cp := *history
+	f.histories[cp.PolicyID] = append(f.histories[cp.PolicyID], &cp)
+	return int64(len(f.histories[cp.PolicyID])), nil
+}
+
+func (f *fakeDAO) ListHistories(_ context.Context, policyID int64) ([]*policy.History, error) {
+	// mirror the real DAO's ORDER BY -version so callers can rely on
+	// newest-first ordering without a database round trip.
+	out := append([]*policy.History(nil), f.histories[policyID]...)
+	sort.Slice(out, func(i, j int) bool { return out[i].Version > out[j].Version })
+	return out, nil
+}
+
+func (f *fakeDAO) GetHistory(_ context.Context, policyID int64, version int64) (*policy.History, error) {
+	for _, h := range f.histories[policyID] {
+		if h.Version == version {
+			return h, nil
+		}
+	}
+	return nil, assert.AnError
+}
+
+// noopTransaction runs f directly against ctx, standing in for
+// orm.WithTransaction so these tests don't need a real database.
+func noopTransaction(f func(ctx context.Context) error) func(ctx context.Context) error {
+	return f
+}
+
+func newTestManager(t *testing.T, d *fakeDAO) *manager {
+	t.Helper()
+	original := withTransaction
+	withTransaction = noopTransaction
+	t.Cleanup(func() { withTransaction = original })
+	return &manager{dao: d}
+}
+
+func TestCreateSnapshotsVersionOne(t *testing.T) {
+	d := newFakeDAO()
+	m := newTestManager(t, d)
+
+	id, err := m.Create(context.Background(), &policy.Schema{Name: "p1"})
+	require.NoError(t, err)
+
+	histories, err := m.ListVersions(context.Background(), id)
+	require.NoError(t, err)
+	require.Len(t, histories, 1)
+	assert.EqualValues(t, 1, histories[0].Version)
+}
+
+func TestUpdateBumpsVersionAndSnapshots(t *testing.T) {
+	d := newFakeDAO()
+	m := newTestManager(t, d)
+
+	id, err := m.Create(context.Background(), &policy.Schema{Name: "p1"})
+	require.NoError(t, err)
+
+	err = m.Update(context.Background(), &policy.Schema{ID: id, Name: "p1-renamed"})
+	require.NoError(t, err)
+
+	histories, err := m.ListVersions(context.Background(), id)
+	require.NoError(t, err)
+	require.Len(t, histories, 2)
+	assert.EqualValues(t, 2, histories[0].Version)
+
+	version, err := m.GetVersion(context.Background(), id, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "p1-renamed", version.Name)
+}
+
+func TestRollbackReIssuesUpdateFromSnapshot(t *testing.T) {
+	d := newFakeDAO()
+	m := newTestManager(t, d)
+
+	id, err := m.Create(context.Background(), &policy.Schema{Name: "p1", Description: "original"})
+	require.NoError(t, err)
+	require.NoError(t, m.Update(context.Background(), &policy.Schema{ID: id, Name: "p1", Description: "changed"}))
+
+	require.NoError(t, m.Rollback(context.Background(), id, 1))
+
+	current, err := d.Get(context.Background(), id)
+	require.NoError(t, err)
+	assert.Equal(t, "original", current.Description)
+
+	histories, err := m.ListVersions(context.Background(), id)
+	require.NoError(t, err)
+	// rollback re-issues an Update, so it creates a new version rather than
+	// rewriting history in place.
+	require.Len(t, histories, 3)
+	assert.Equal(t, "original", histories[0].Description)
+}
+
+func TestCreateDoesNotSnapshotWhenCreateFails(t *testing.T) {
+	d := newFakeDAO()
+	d.createErr = assert.AnError
+	m := newTestManager(t, d)
+
+	_, err := m.Create(context.Background(), &policy.Schema{Name: "p1"})
+	assert.Error(t, err)
+	assert.Empty(t, d.histories)
+}
@@ -0,0 +1,74 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package preheat implements the v2.0 REST handlers for preheat policies.
+// This slice of the codebase carries only the preview endpoint; the rest of
+// the policy CRUD handlers and the generated router that dispatches to them
+// live alongside it in the full v2.0 API server.
+package preheat
+
+import (
+	"context"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/p2p/preheat/models/policy"
+	pkgpolicy "github.com/goharbor/harbor/src/pkg/p2p/preheat/policy"
+)
+
+// PreviewMethod and PreviewRoute document the operation this handler
+// implements: POST /projects/{id}/preheat/policies/preview. The v2.0 router
+// owns the actual route table; it extracts projectID from the {id} segment
+// and decodes the request body into a PreviewRequest before calling Preview,
+// the same way it does for every other preheat policy operation.
+const (
+	PreviewMethod = "POST"
+	PreviewRoute  = "/projects/{id}/preheat/policies/preview"
+)
+
+// Handler serves the preheat policy v2.0 API.
+type Handler struct {
+	Mgr pkgpolicy.Manager
+}
+
+// NewHandler creates a Handler backed by the global policy manager.
+func NewHandler() *Handler {
+	return &Handler{Mgr: pkgpolicy.Mgr}
+}
+
+// PreviewRequest is the decoded body of a preview request: the policy schema
+// to dry-run, plus optional keywords further narrowing the candidate
+// artifact set.
+type PreviewRequest struct {
+	Policy *policy.Schema
+	Q      map[string]interface{}
+}
+
+// Preview dry-runs req.Policy's filters against projectID's current artifact
+// set, without persisting the policy or enqueuing preheat jobs. It resolves
+// through the same policy.Manager.Evaluate path used internally, so a
+// preview can never diverge from what saving and enabling the policy would
+// actually preheat.
+func (h *Handler) Preview(ctx context.Context, projectID int64, req *PreviewRequest) (*pkgpolicy.EvaluateResult, error) {
+	if req == nil || req.Policy == nil {
+		return nil, errors.New("policy is required")
+	}
+
+	opts := pkgpolicy.EvaluateOptions{ProjectID: projectID}
+	if len(req.Q) > 0 {
+		opts.Query = &q.Query{Keywords: req.Q}
+	}
+
+	return h.Mgr.Evaluate(ctx, req.Policy, opts)
+}
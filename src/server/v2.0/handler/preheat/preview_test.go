@@ -0,0 +1,68 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preheat
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goharbor/harbor/src/pkg/p2p/preheat/models/policy"
+	pkgpolicy "github.com/goharbor/harbor/src/pkg/p2p/preheat/policy"
+)
+
+type stubManager struct {
+	pkgpolicy.Manager
+	lastSchema *policy.Schema
+	lastOpts   pkgpolicy.EvaluateOptions
+	result     *pkgpolicy.EvaluateResult
+	err        error
+}
+
+func (s *stubManager) Evaluate(_ context.Context, schema *policy.Schema, opts pkgpolicy.EvaluateOptions) (*pkgpolicy.EvaluateResult, error) {
+	s.lastSchema = schema
+	s.lastOpts = opts
+	return s.result, s.err
+}
+
+func TestHandlerPreview(t *testing.T) {
+	mgr := &stubManager{result: &pkgpolicy.EvaluateResult{Matched: []*policy.Artifact{{Repository: "library/hello"}}}}
+	h := &Handler{Mgr: mgr}
+
+	result, err := h.Preview(context.Background(), 3, &PreviewRequest{
+		Policy: &policy.Schema{Name: "p1"},
+		Q:      map[string]interface{}{"repository": "library/hello"},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Matched, 1)
+	assert.Equal(t, "library/hello", result.Matched[0].Repository)
+
+	assert.EqualValues(t, 3, mgr.lastOpts.ProjectID)
+	require.NotNil(t, mgr.lastOpts.Query)
+	assert.Equal(t, "library/hello", mgr.lastOpts.Query.Keywords["repository"])
+	assert.Equal(t, "p1", mgr.lastSchema.Name)
+}
+
+func TestHandlerPreviewRequiresPolicy(t *testing.T) {
+	h := &Handler{Mgr: &stubManager{}}
+
+	_, err := h.Preview(context.Background(), 3, &PreviewRequest{})
+	assert.Error(t, err)
+
+	_, err = h.Preview(context.Background(), 3, nil)
+	assert.Error(t, err)
+}